@@ -0,0 +1,96 @@
+package mycontext
+
+import "sync"
+
+// AfterFunc安排f在ctx被取消（无论原因是什么）之后，在它自己的goroutine中运行，并返回一个
+// stop函数用于取消这次注册。如果ctx已经被取消，AfterFunc会立即在新的goroutine中调用f。
+//
+// 对同一个ctx多次调用AfterFunc是相互独立的：调用f的goroutine互不影响，一个注册不会替换另一个。
+//
+// 调用返回的stop函数会取消f与ctx的关联。它返回true表示成功阻止了f的运行；如果f已经运行或者
+// 已经被stop过，则返回false。stop不会等待已经启动的f运行结束。
+//
+// AfterFunc使得调用方无需为每一个回调都启动一个形如
+//
+//	select {
+//	case <-ctx.Done():
+//	}
+//
+// 的goroutine。
+func AfterFunc(ctx Context, f func()) (stop func() bool) {
+	a := &afterFuncCtx{f: f, done: make(chan struct{})}
+	if cc, ok := lookupCancelCtx(ctx); ok {
+		a.register(cc)
+		return a.stop
+	}
+	// ctx不是由cancelCtx/timerCtx派生的（例如一个外部的Context实现），通过
+	// propagateCancel注册一个合成的canceler，复用它对“外部Context”已有的处理方式。
+	propagateCancel(ctx, a)
+	return a.stop
+}
+
+// afterFuncCtx是AfterFunc注册的canceler。它既可以作为cancelCtx.afterFuncs中的一员被直接
+// 触发，也可以作为propagateCancel眼中的一个普通child，用于无法识别为*cancelCtx的parent。
+type afterFuncCtx struct {
+	f func()
+
+	mu      sync.Mutex
+	stopped bool
+	done    chan struct{} // 在cancel/stop第一次生效时关闭，让propagateCancel的兜底goroutine能退出
+	parent  *cancelCtx    // 仅在通过register直接挂到某个cancelCtx上时非nil
+}
+
+func (a *afterFuncCtx) register(parent *cancelCtx) {
+	parent.mu.Lock()
+	if parent.err != nil {
+		parent.mu.Unlock()
+		a.cancel(false, parent.err, parent.cause)
+		return
+	}
+	if parent.afterFuncs == nil {
+		parent.afterFuncs = make(map[*afterFuncCtx]struct{})
+	}
+	parent.afterFuncs[a] = struct{}{}
+	a.parent = parent
+	parent.mu.Unlock()
+}
+
+// cancel实现canceler接口：它在f还没有运行过的情况下，用一个新的goroutine运行f，并关闭
+// a.done。关闭done是必要的：当parent是propagateCancel无法识别为*cancelCtx的外部Context
+// 时，AfterFunc靠一个select{case <-parent.Done(): ...; case <-a.Done(): }的goroutine来
+// 等待这两件事之一发生，如果a.Done()永远不关闭，stop()就没有办法让那个goroutine退出。
+func (a *afterFuncCtx) cancel(removeFromParent bool, err, cause error) {
+	a.mu.Lock()
+	stopped := a.stopped
+	a.stopped = true
+	if !stopped {
+		close(a.done)
+	}
+	a.mu.Unlock()
+	if !stopped {
+		go a.f()
+	}
+}
+
+// Done让afterFuncCtx满足canceler接口要求的Done() <-chan struct{}；它在cancel或stop第一次
+// 生效时关闭一次，供propagateCancel里等待外部Context取消的兜底goroutine在stop()之后退出。
+func (a *afterFuncCtx) Done() <-chan struct{} {
+	return a.done
+}
+
+// stop取消f与ctx的关联，报告是否在f运行之前成功阻止了它。
+func (a *afterFuncCtx) stop() bool {
+	a.mu.Lock()
+	stopped := a.stopped
+	a.stopped = true
+	if !stopped {
+		close(a.done)
+	}
+	a.mu.Unlock()
+	if a.parent != nil {
+		a.parent.mu.Lock()
+		delete(a.parent.afterFuncs, a)
+		a.parent.mu.Unlock()
+	}
+	return !stopped
+}
@@ -0,0 +1,102 @@
+package mycontext
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+// foreignCtx is a Context implementation that AfterFunc cannot reach through
+// lookupCancelCtx (it is not derived from *cancelCtx/*timerCtx), forcing the
+// propagateCancel fallback goroutine path.
+type foreignCtx struct {
+	done chan struct{}
+}
+
+func (c *foreignCtx) Deadline() (deadline time.Time, ok bool) {
+	return time.Time{}, false
+}
+
+func (c *foreignCtx) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *foreignCtx) Err() error {
+	select {
+	case <-c.done:
+		return CanceledError
+	default:
+		return nil
+	}
+}
+
+func (c *foreignCtx) Value(key interface{}) interface{} {
+	return nil
+}
+
+func TestAfterFuncRunsOnCancel(t *testing.T) {
+	bg := testBackgroundCtx{}
+	ctx, cancel := WithCancel(bg)
+	ran := make(chan struct{})
+	AfterFunc(ctx, func() { close(ran) })
+
+	cancel()
+
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("AfterFunc callback did not run after cancel")
+	}
+}
+
+func TestAfterFuncStopPreventsCallback(t *testing.T) {
+	bg := testBackgroundCtx{}
+	ctx, cancel := WithCancel(bg)
+	defer cancel()
+	ran := make(chan struct{})
+	stop := AfterFunc(ctx, func() { close(ran) })
+
+	if !stop() {
+		t.Fatal("stop() = false, want true before cancel")
+	}
+
+	cancel()
+
+	select {
+	case <-ran:
+		t.Fatal("AfterFunc callback ran after stop()")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// TestAfterFuncForeignContextStopUnblocksFallbackGoroutine guards against the
+// propagateCancel fallback goroutine (spawned for parents AfterFunc can't walk
+// to a *cancelCtx) leaking forever when stop() is called before the foreign
+// parent ever cancels.
+func TestAfterFuncForeignContextStopUnblocksFallbackGoroutine(t *testing.T) {
+	parent := &foreignCtx{done: make(chan struct{})}
+	ran := make(chan struct{})
+	stop := AfterFunc(parent, func() { close(ran) })
+
+	runtime.Gosched()
+	before := runtime.NumGoroutine()
+
+	if !stop() {
+		t.Fatal("stop() = false, want true before parent ever cancels")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() >= before && time.Now().Before(deadline) {
+		runtime.Gosched()
+		time.Sleep(time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n >= before {
+		t.Fatalf("fallback goroutine appears to have leaked after stop(): NumGoroutine before=%d after=%d", before, n)
+	}
+
+	select {
+	case <-ran:
+		t.Fatal("AfterFunc callback ran after stop()")
+	default:
+	}
+}
@@ -0,0 +1,40 @@
+package mycontext
+
+import "time"
+
+// WithDeadlineCause 和WithDeadline类似，但是额外指定了超时发生时的cause：之后通过
+// Cause(ctx)取到的就是这里传入的cause，而不是笼统的DeadlineExceeded。
+//
+// 如果在截止时间到达之前调用方自己调用了返回的cancel，Cause取出的将是CanceledError，
+// 而不是这里传入的cause——cause只描述“超时”这一种取消原因。
+func WithDeadlineCause(parent Context, deadline time.Time, cause error) (Context, CancelFunc) {
+	if cur, ok := parent.Deadline(); ok && cur.Before(deadline) {
+		// parent的截止时间已经比新的deadline更早了。
+		return WithCancel(parent)
+	}
+	c := &timerCtx{
+		cancelCtx: newCancelCtx(parent),
+		deadline:  deadline,
+	}
+	propagateCancel(parent, c)
+	d := deadline.Sub(time.Now())
+	if d <= 0 {
+		c.cancel(true, DeadlineExceeded, cause) // 截止时间已经过了
+		return c, func() { c.cancel(true, CanceledError, nil) }
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.timer = time.AfterFunc(d, func() {
+			c.cancel(true, DeadlineExceeded, cause)
+		})
+	}
+	return c, func() { c.cancel(true, CanceledError, nil) }
+}
+
+// WithTimeoutCause 等价于WithDeadlineCause(parent, time.Now().Add(timeout), cause)。
+//
+// 取消此context会释放与其关联的资源，因此代码应该在该context中运行的操作完成后立即调用cancel。
+func WithTimeoutCause(parent Context, timeout time.Duration, cause error) (Context, CancelFunc) {
+	return WithDeadlineCause(parent, time.Now().Add(timeout), cause)
+}
@@ -0,0 +1,35 @@
+package mycontext
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithDeadlineCauseRecordsCauseOnTimeout(t *testing.T) {
+	bg := testBackgroundCtx{}
+	myErr := errors.New("DB query budget exhausted")
+	ctx, cancel := WithDeadlineCause(bg, time.Now().Add(-time.Second), myErr)
+	defer cancel()
+
+	if err := ctx.Err(); err != DeadlineExceeded {
+		t.Fatalf("ctx.Err() = %v, want DeadlineExceeded", err)
+	}
+	if cause := Cause(ctx); cause != myErr {
+		t.Fatalf("Cause(ctx) = %v, want %v", cause, myErr)
+	}
+}
+
+func TestWithTimeoutCauseExplicitCancelDiscardsCause(t *testing.T) {
+	bg := testBackgroundCtx{}
+	myErr := errors.New("DB query budget exhausted")
+	ctx, cancel := WithTimeoutCause(bg, time.Hour, myErr)
+	cancel()
+
+	if err := ctx.Err(); err != CanceledError {
+		t.Fatalf("ctx.Err() = %v, want CanceledError", err)
+	}
+	if cause := Cause(ctx); cause != CanceledError {
+		t.Fatalf("Cause(ctx) = %v, want CanceledError (explicit cancel discards the construction-time cause)", cause)
+	}
+}
@@ -0,0 +1,23 @@
+package mycontext
+
+import "time"
+
+// testBackgroundCtx是测试专用的根Context：它从不被取消、没有截止时间、也不携带任何值。
+// 包里目前还没有导出的Background实现，测试用它来充当bg，不牵扯main.go里那个未完成的调用。
+type testBackgroundCtx struct{}
+
+func (testBackgroundCtx) Deadline() (deadline time.Time, ok bool) {
+	return time.Time{}, false
+}
+
+func (testBackgroundCtx) Done() <-chan struct{} {
+	return nil
+}
+
+func (testBackgroundCtx) Err() error {
+	return nil
+}
+
+func (testBackgroundCtx) Value(key interface{}) interface{} {
+	return nil
+}
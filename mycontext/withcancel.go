@@ -19,7 +19,7 @@ type CancelFunc func()
 func WithCancel(parent Context) (ctx Context, cancel CancelFunc) {
 	c := newCancelCtx(parent)
 	propagateCancel(parent, &c)
-	return &c, func() { c.cancel(true, CanceledError) }
+	return &c, func() { c.cancel(true, CanceledError, nil) }
 }
 
 // newCancelCtx returns an initialized cancelCtx.
@@ -32,19 +32,26 @@ func newCancelCtx(parent Context) cancelCtx {
 
 // 一个canceler是一个可以直接取消的context类型。其实现是*cancelCtx和*timerCtx。
 type canceler interface {
-	cancel(removeFromParent bool, err error)
+	cancel(removeFromParent bool, err, cause error)
 	Done() <-chan struct{}
 }
 
+// cancelCtxKey是一个哨兵key，cancelCtx.Value用它来让parentCancelCtx能够沿着任意
+// Context派生链（valueCtx、timerCtx……）找到最近的*cancelCtx，而不必要求parent直接就是
+// *cancelCtx。
+type cancelCtxKey struct{}
+
 // 一个cancelCtx可以被取消。当被取消时，它也会取消任何实现了canceller的child
 type cancelCtx struct {
 	Context
 
 	done chan struct{} // closed by the first cancel call.
 
-	mu       sync.Mutex
-	children map[canceler]bool // 被第一个cancel call设置为nil
-	err      error             // 被第一次cancel call设置为not-nil
+	mu         sync.Mutex
+	children   map[canceler]bool          // 被第一个cancel call设置为nil
+	err        error                      // 被第一次cancel call设置为not-nil
+	cause      error                      // 被第一次cancel call设置为not-nil，记录取消的真正原因
+	afterFuncs map[*afterFuncCtx]struct{} // 通过AfterFunc注册的回调，被第一个cancel call设置为nil
 }
 
 func (c *cancelCtx) Done() <-chan struct{} {
@@ -61,23 +68,43 @@ func (c *cancelCtx) String() string {
 	return fmt.Sprintf("%v.WithCancel", c.Context)
 }
 
+// Value重载了内嵌Context的Value：当key是cancelCtxKey{}时返回c自己，以便parentCancelCtx
+// 可以把它当作派生链上的一个“路标”来识别；其他key则委托给parent。
+func (c *cancelCtx) Value(key interface{}) interface{} {
+	if key == (cancelCtxKey{}) {
+		return c
+	}
+	return c.Context.Value(key)
+}
+
 // cancel 关闭c.done，取消c的每一个子节点，并且如果removeFromParent为真，则将c从其父的children中移除。
-func (c *cancelCtx) cancel(removeFromParent bool, err error) {
+// cause记录取消的真正原因：如果为nil，则取cause等于err本身（即调用方没有通过
+// *Cause系列接口提供更具体的原因）。
+func (c *cancelCtx) cancel(removeFromParent bool, err, cause error) {
 	if err == nil {
 		panic("context: internal error: missing cancel error")
 	}
+	if cause == nil {
+		cause = err
+	}
 	c.mu.Lock()
 	if c.err != nil {
 		c.mu.Unlock()
 		return // already canceled
 	}
 	c.err = err
+	c.cause = cause
 	close(c.done)
 	for child := range c.children {
 		// NOTE: acquiring the child's lock while holding parent's lock.
-		child.cancel(false, err)
+		child.cancel(false, err, cause)
 	}
 	c.children = nil
+	for af := range c.afterFuncs {
+		// af.cancel在内部用go f()启动回调，这里不需要再额外开goroutine。
+		af.cancel(false, err, cause)
+	}
+	c.afterFuncs = nil
 	c.mu.Unlock()
 
 	if removeFromParent {
@@ -94,7 +121,7 @@ func propagateCancel(parent Context, child canceler) {
 		p.mu.Lock()
 		if p.err != nil {
 			// parent has already been canceled
-			child.cancel(false, p.err)
+			child.cancel(false, p.err, p.cause)
 		} else {
 			if p.children == nil {
 				p.children = make(map[canceler]bool)
@@ -106,23 +133,37 @@ func propagateCancel(parent Context, child canceler) {
 		go func() {
 			select {
 			case <-parent.Done():
-				child.cancel(false, parent.Err())
+				child.cancel(false, parent.Err(), Cause(parent))
 			case <-child.Done():
 			}
 		}()
 	}
 }
 
-// parentCancelCtx 跟随一个父引用链，直到找到一个*cancelCtx
+// parentCancelCtx 沿着parent的派生链查找最近的*cancelCtx（无论中间隔着多少层valueCtx、
+// timerCtx之类的包装），而不要求parent直接就是*cancelCtx。
+//
+// 查找通过parent.Value(cancelCtxKey{})完成：每个*cancelCtx都会在Value里把自己暴露给这个
+// 哨兵key，valueCtx默认把未命中的key转发给它内嵌的Context，于是这个调用天然能穿透任意层
+// valueCtx/timerCtx。withoutCancelCtx则特意不转发这个key，使WithoutCancel成为一个真正的
+// 边界：它之下的取消不会传播到它之上的祖先。
+//
+// 找到候选者之后，还会用parent.Done()与候选者的done通道做一次比对：如果两者不一致，说明
+// parent是一个我们认不出来的自定义Context实现（它内部可能包了一个*cancelCtx但没有如实
+// 转发Done），这时放弃复用，交回给propagateCancel走goroutine兜底的老路。
 func parentCancelCtx(parent Context) (*cancelCtx, bool) {
-	for {
-		switch c := parent.(type) {
-		case *cancelCtx:
-			return c, true
-		default:
-			return nil, false
-		}
+	done := parent.Done()
+	if done == nil {
+		return nil, false
+	}
+	p, ok := parent.Value(cancelCtxKey{}).(*cancelCtx)
+	if !ok {
+		return nil, false
+	}
+	if p.done != done {
+		return nil, false
 	}
+	return p, true
 }
 
 // removeChild 将一个context从其父代中移除。
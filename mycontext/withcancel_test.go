@@ -0,0 +1,60 @@
+package mycontext
+
+import "testing"
+
+// TestWithCancelWithValueWithCancelChainUsesSentinelNotGoroutine覆盖
+// WithCancel(WithValue(WithCancel(bg)))这种链式派生：grandchild应该能穿过中间的
+// valueCtx，把自己直接注册到grandparent的children里（说明parentCancelCtx靠
+// cancelCtxKey哨兵找到了grandparent，而不是退化成propagateCancel里的goroutine兜底），
+// 并且在child被取消之后，从grandparent的children中移除。
+func TestWithCancelWithValueWithCancelChainUsesSentinelNotGoroutine(t *testing.T) {
+	bg := testBackgroundCtx{}
+	grandparent, cancelGrandparent := WithCancel(bg)
+	defer cancelGrandparent()
+
+	gp, ok := grandparent.(*cancelCtx)
+	if !ok {
+		t.Fatalf("WithCancel(bg) returned %T, want *cancelCtx", grandparent)
+	}
+
+	mid := WithValue(grandparent, "k", "v")
+	child, cancelChild := WithCancel(mid)
+
+	gp.mu.Lock()
+	n := len(gp.children)
+	gp.mu.Unlock()
+	if n != 1 {
+		t.Fatalf("grandparent.children has %d entries right after WithCancel(WithValue(...)), want 1 "+
+			"(registration should be synchronous via the cancelCtxKey sentinel, not the goroutine fallback)", n)
+	}
+
+	cancelChild()
+
+	if err := child.Err(); err != CanceledError {
+		t.Fatalf("child.Err() = %v, want CanceledError", err)
+	}
+
+	gp.mu.Lock()
+	n = len(gp.children)
+	gp.mu.Unlock()
+	if n != 0 {
+		t.Fatalf("grandparent.children has %d entries after child was canceled, want 0", n)
+	}
+}
+
+func TestWithCancelCancelsDescendants(t *testing.T) {
+	bg := testBackgroundCtx{}
+	parent, cancelParent := WithCancel(bg)
+	child, _ := WithCancel(parent)
+
+	cancelParent()
+
+	select {
+	case <-child.Done():
+	default:
+		t.Fatal("child.Done() was not closed after parent was canceled")
+	}
+	if err := child.Err(); err != CanceledError {
+		t.Fatalf("child.Err() = %v, want CanceledError", err)
+	}
+}
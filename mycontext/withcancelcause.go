@@ -0,0 +1,39 @@
+package mycontext
+
+// CancelCauseFunc 和CancelFunc类似，但是允许调用方附带一个cause，说明context被取消的
+// 真正原因。第一次调用之后，对CancelCauseFunc的后续调用将不起任何作用。
+type CancelCauseFunc func(cause error)
+
+// WithCancelCause 与WithCancel类似，返回带有新的Done通道的parent的副本，但是返回的
+// cancel函数是一个CancelCauseFunc：调用它时可以附带一个cause，之后可以通过Cause(ctx)
+// 取出该原因。如果cause为nil，则效果与WithCancel的cancel函数相同。
+//
+// 取消此context会释放与其关联的资源，因此代码应该在该context中运行的操作完成后立即调用cancel。
+func WithCancelCause(parent Context) (ctx Context, cancel CancelCauseFunc) {
+	c := newCancelCtx(parent)
+	propagateCancel(parent, &c)
+	return &c, func(cause error) { c.cancel(true, CanceledError, cause) }
+}
+
+// Cause 返回一个非空的原因说明ctx被取消的真正原因：它优先返回最近一次通过
+// WithCancelCause、WithDeadlineCause或WithTimeoutCause记录下来的cause，如果ctx或其
+// 祖先都没有记录过cause，则返回ctx.Err()。
+//
+// 如果ctx尚未被取消，Cause返回nil。
+func Cause(ctx Context) error {
+	cc, ok := lookupCancelCtx(ctx)
+	if !ok {
+		return ctx.Err()
+	}
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	return cc.cause
+}
+
+// lookupCancelCtx 沿着ctx的派生链查找最近的*cancelCtx（无论它是被直接创建的，还是像
+// timerCtx那样被内嵌），用于取出该节点记录的cause。它与parentCancelCtx共用同一套
+// cancelCtxKey哨兵机制，因此同样会在WithoutCancel处停下来。
+func lookupCancelCtx(ctx Context) (*cancelCtx, bool) {
+	cc, ok := ctx.Value(cancelCtxKey{}).(*cancelCtx)
+	return cc, ok
+}
@@ -0,0 +1,40 @@
+package mycontext
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithCancelCauseRecordsCause(t *testing.T) {
+	bg := testBackgroundCtx{}
+	ctx, cancel := WithCancelCause(bg)
+	myErr := errors.New("boom")
+	cancel(myErr)
+
+	if err := ctx.Err(); err != CanceledError {
+		t.Fatalf("ctx.Err() = %v, want CanceledError", err)
+	}
+	if cause := Cause(ctx); cause != myErr {
+		t.Fatalf("Cause(ctx) = %v, want %v", cause, myErr)
+	}
+}
+
+func TestCauseFallsBackToErrWithoutCause(t *testing.T) {
+	bg := testBackgroundCtx{}
+	ctx, cancel := WithCancel(bg)
+	cancel()
+
+	if cause := Cause(ctx); cause != CanceledError {
+		t.Fatalf("Cause(ctx) = %v, want CanceledError", cause)
+	}
+}
+
+func TestCauseNilBeforeCancellation(t *testing.T) {
+	bg := testBackgroundCtx{}
+	ctx, cancel := WithCancelCause(bg)
+	defer cancel(nil)
+
+	if cause := Cause(ctx); cause != nil {
+		t.Fatalf("Cause(ctx) = %v, want nil before cancellation", cause)
+	}
+}
@@ -0,0 +1,49 @@
+package mycontext
+
+import (
+	"fmt"
+	"time"
+)
+
+// WithoutCancel返回一个parent的副本，该副本永远不会被取消：它的Done永远返回nil、Err永远
+// 返回nil、Deadline永远返回(time.Time{}, false)，与parent当前是否已经被取消、是否设置了
+// 截止时间无关。对Value的查找仍然委托给parent，因此trace ID、user ID这类通过WithValue
+// 传递的请求范围内的值会被保留下来。
+//
+// 典型用法是：HTTP handler在请求对应的context已经被取消之后，仍然需要启动一个后台的清理
+// 或审计goroutine，但希望继续携带请求范围内的值。
+func WithoutCancel(parent Context) Context {
+	return withoutCancelCtx{parent}
+}
+
+// withoutCancelCtx包装parent，阻断它的取消/超时信号，同时保留Value查找。它也是
+// parentCancelCtx搜索*cancelCtx祖先时的一个边界：child的取消不应该越过这个边界去影响
+// withoutCancelCtx之上的祖先（以及反过来，withoutCancelCtx自己也不会被祖先取消）。
+type withoutCancelCtx struct {
+	c Context
+}
+
+func (withoutCancelCtx) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (withoutCancelCtx) Done() <-chan struct{} {
+	return nil
+}
+
+func (withoutCancelCtx) Err() error {
+	return nil
+}
+
+func (c withoutCancelCtx) Value(key interface{}) interface{} {
+	if key == (cancelCtxKey{}) {
+		// 不把parent的*cancelCtx暴露给parentCancelCtx，这样WithoutCancel就成为了
+		// 取消传播路径上真正的边界。
+		return nil
+	}
+	return c.c.Value(key)
+}
+
+func (c withoutCancelCtx) String() string {
+	return fmt.Sprintf("%v.WithoutCancel", c.c)
+}
@@ -0,0 +1,48 @@
+package mycontext
+
+import "testing"
+
+func TestWithoutCancelIgnoresParentCancellation(t *testing.T) {
+	bg := testBackgroundCtx{}
+	parent, cancelParent := WithCancel(bg)
+	valued := WithValue(parent, "k", "v")
+	detached := WithoutCancel(valued)
+
+	cancelParent()
+
+	if err := detached.Err(); err != nil {
+		t.Fatalf("detached.Err() = %v, want nil after parent was canceled", err)
+	}
+	if done := detached.Done(); done != nil {
+		t.Fatalf("detached.Done() = %v, want nil", done)
+	}
+	if d, ok := detached.Deadline(); ok {
+		t.Fatalf("detached.Deadline() = (%v, true), want ok=false", d)
+	}
+	if v := detached.Value("k"); v != "v" {
+		t.Fatalf("detached.Value(\"k\") = %v, want \"v\"", v)
+	}
+}
+
+func TestWithoutCancelIsAPropagationBoundary(t *testing.T) {
+	bg := testBackgroundCtx{}
+	parent, cancelParent := WithCancel(bg)
+	defer cancelParent()
+
+	pp, ok := parent.(*cancelCtx)
+	if !ok {
+		t.Fatalf("WithCancel(bg) returned %T, want *cancelCtx", parent)
+	}
+
+	detached := WithoutCancel(parent)
+	_, cancelChild := WithCancel(detached)
+	defer cancelChild()
+
+	pp.mu.Lock()
+	n := len(pp.children)
+	pp.mu.Unlock()
+	if n != 0 {
+		t.Fatalf("parent.children has %d entries, want 0: WithCancel(WithoutCancel(parent)) "+
+			"must not register through the WithoutCancel boundary", n)
+	}
+}